@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/concourse/baggageclaim/auth"
+)
+
+// AuthConfig wraps the API's handlers with JWT bearer-token authorization.
+// A nil Validator disables the check and every request is served;
+// Allowlist names request paths (e.g. "/healthz") that should be served
+// without a token.
+type AuthConfig struct {
+	Validator auth.Validator
+	Allowlist []string
+}
+
+func (c AuthConfig) allowed(path string) bool {
+	for _, p := range c.Allowlist {
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wrap returns handler wrapped so that every request not on the allowlist
+// must carry a bearer token authorizing the handle being operated on. The
+// handle is taken from the second path segment, i.e. /volumes/:handle/...;
+// requests to /volumes or /volumes/ (list, create) are checked against the
+// wildcard handle.
+func Wrap(handler http.Handler, config AuthConfig) http.Handler {
+	if config.Validator == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.allowed(r.URL.Path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		token := bearerToken(r)
+		handle := handleFromPath(r.URL.Path)
+
+		if err := config.Validator.Validate(token, handle); err != nil {
+			RespondWithError(w, err, http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimPrefix(header, "Bearer ")
+}
+
+func handleFromPath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	if len(segments) < 2 || segments[1] == "" {
+		return auth.AnyHandle
+	}
+
+	return segments[1]
+}
@@ -0,0 +1,71 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/api"
+	"github.com/concourse/baggageclaim/auth"
+)
+
+var _ = Describe("Wrap", func() {
+	var (
+		inner   http.Handler
+		called  bool
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		called = false
+		inner = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	Context("when no validator is configured", func() {
+		BeforeEach(func() {
+			handler = api.Wrap(inner, api.AuthConfig{})
+		})
+
+		It("serves every request", func() {
+			req := httptest.NewRequest("GET", "/volumes/some-handle", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(called).To(BeTrue())
+		})
+	})
+
+	Context("when a validator is configured", func() {
+		BeforeEach(func() {
+			handler = api.Wrap(inner, api.AuthConfig{
+				Validator: auth.NewHMACValidator([]byte("secret")),
+				Allowlist: []string{"/healthz"},
+			})
+		})
+
+		It("allows allow-listed paths through without a token", func() {
+			req := httptest.NewRequest("GET", "/healthz", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(called).To(BeTrue())
+		})
+
+		It("responds 401 when no token is provided", func() {
+			req := httptest.NewRequest("DELETE", "/volumes/some-handle", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			Expect(called).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
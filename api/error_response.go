@@ -0,0 +1,20 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type ErrorResponse struct {
+	Message string `json:"error"`
+}
+
+// RespondWithError writes a JSON error body with the given status code.
+func RespondWithError(w http.ResponseWriter, err error, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// ReceiveDeltaHandler serves PUT /volumes/:handle/receive-delta?parent=:parent.
+// It pipes the request body - a `btrfs send -p parent` stream - straight
+// into driver.ReceiveDelta, never through a tar extractor, landing the
+// result at :handle, which the caller must already have created as a COW
+// snapshot of :parent (see HTTPReplicationDestination.ReceiveDelta).
+func ReceiveDeltaHandler(provider VolumeContentsProvider, driver volume.ReceiveDriver, handleParam func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handle := handleParam(r)
+		parent := r.URL.Query().Get("parent")
+
+		destPath, found, err := provider.VolumePath(handle)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			RespondWithError(w, volume.ErrVolumeDoesNotExist, http.StatusNotFound)
+			return
+		}
+
+		parentPath, found, err := provider.VolumePath(parent)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			RespondWithError(w, fmt.Errorf("receive-delta: parent volume %q not found", parent), http.StatusBadRequest)
+			return
+		}
+
+		if err := driver.ReceiveDelta(destPath, parentPath, r.Body); err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
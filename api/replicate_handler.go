@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// ReplicationSource locates a volume's on-disk root and, if it was
+// created as a COW snapshot, the handle of its parent, so ReplicateHandler
+// can hand both to a volume.Replicator.
+type ReplicationSource interface {
+	VolumePath(handle string) (string, bool, error)
+	ParentHandle(handle string) (string, bool, error)
+}
+
+// DestinationDialer opens a volume.ReplicationDestination talking to the
+// given baggageclaim base URL, authorizing its requests for newHandle.
+type DestinationDialer func(destination string, newHandle string) volume.ReplicationDestination
+
+// ReplicateHandler serves POST /volumes/:handle/replicate: it pushes a
+// copy of :handle directly to another baggageclaim, so that server is
+// pre-warmed with the volume before a build is scheduled there, without
+// routing the stream through the caller.
+func ReplicateHandler(source ReplicationSource, replicator *volume.Replicator, dial DestinationDialer, handleParam func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handle := handleParam(r)
+
+		_, found, err := source.VolumePath(handle)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			RespondWithError(w, volume.ErrVolumeDoesNotExist, http.StatusNotFound)
+			return
+		}
+
+		var req baggageclaim.ReplicationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondWithError(w, fmt.Errorf("malformed replication request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		parent, _, err := source.ParentHandle(handle)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		dest := dial(req.Destination, req.Handle)
+
+		if err := replicator.Replicate(dest, handle, parent, req.Handle, req.Privileged); err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
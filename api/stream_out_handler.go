@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/baggageclaim/metrics"
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// VolumeContentsProvider locates the on-disk root of a volume and its
+// cached tar layout, so StreamOutHandler can serve both full and ranged
+// requests without re-deriving either.
+type VolumeContentsProvider interface {
+	VolumePath(handle string) (string, bool, error)
+	TarIndex(handle string) (*volume.TarIndex, error)
+}
+
+// StreamOutHandler serves GET /volumes/:handle/stream-out, honoring a
+// Range: bytes=N- request header by responding 206 Partial Content and
+// resuming the deterministic tar stream at N; with no Range header it
+// behaves exactly as before.
+func StreamOutHandler(provider VolumeContentsProvider, handleParam func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handle := handleParam(r)
+
+		root, found, err := provider.VolumePath(handle)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			RespondWithError(w, volume.ErrVolumeDoesNotExist, http.StatusNotFound)
+			return
+		}
+
+		index, err := provider.TarIndex(handle)
+		if err != nil {
+			RespondWithError(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Accept-Ranges", "bytes")
+
+		offset, ranged, err := parseRangeOffset(r.Header.Get("Range"), index.Total)
+		if err != nil {
+			RespondWithError(w, err, http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+
+		if ranged {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, index.Total-1, index.Total))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		counter := &byteCountingWriter{ResponseWriter: w}
+		defer func() {
+			metrics.StreamBytesTotal.WithLabelValues("out").Add(float64(counter.written))
+		}()
+
+		if err := volume.StreamOutFrom(counter, root, index, offset); err != nil {
+			return
+		}
+	}
+}
+
+// byteCountingWriter tracks how many bytes are written through it, so the
+// handler can report the real stream-out size to StreamBytesTotal even
+// when the request ends early (client disconnect, write error).
+type byteCountingWriter struct {
+	http.ResponseWriter
+	written int64
+}
+
+func (w *byteCountingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// parseRangeOffset parses a "bytes=N-" Range header. Other range forms
+// (suffix ranges, explicit end, multipart ranges) aren't needed for
+// resuming a single sequential stream, so they're rejected.
+func parseRangeOffset(header string, total int64) (offset int64, ranged bool, err error) {
+	if header == "" {
+		return 0, false, nil
+	}
+
+	if !strings.HasPrefix(header, "bytes=") {
+		return 0, false, fmt.Errorf("unsupported Range unit: %q", header)
+	}
+
+	spec := strings.TrimPrefix(header, "bytes=")
+	if !strings.HasSuffix(spec, "-") || strings.Contains(spec, ",") {
+		return 0, false, fmt.Errorf("unsupported Range format: %q", header)
+	}
+
+	offset, err = strconv.ParseInt(strings.TrimSuffix(spec, "-"), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid Range offset: %q", header)
+	}
+
+	if offset < 0 || offset >= total {
+		return 0, false, fmt.Errorf("Range offset %d out of bounds for %d byte stream", offset, total)
+	}
+
+	return offset, true, nil
+}
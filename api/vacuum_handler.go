@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+// VacuumStatus is the per-handle outcome reported by the /vacuum endpoint.
+type VacuumStatus struct {
+	Handle string `json:"handle"`
+	Kind   string `json:"kind"`
+	Error  string `json:"error,omitempty"`
+}
+
+// VacuumHandler triggers a synchronous vacuum run on demand and returns
+// the status of every candidate it processed.
+func VacuumHandler(vac *volume.Vacuum) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statuses := []VacuumStatus{}
+
+		for result := range vac.Run(context.Background()) {
+			status := VacuumStatus{
+				Handle: result.Handle,
+				Kind:   string(result.Kind),
+			}
+
+			if result.Err != nil {
+				status.Error = result.Err.Error()
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(statuses)
+	}
+}
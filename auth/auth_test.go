@@ -0,0 +1,72 @@
+package auth_test
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/auth"
+)
+
+func signedToken(secret []byte, handle string, expiresAt time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Handle: handle,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+
+	signed, err := token.SignedString(secret)
+	Expect(err).NotTo(HaveOccurred())
+
+	return signed
+}
+
+var _ = Describe("Validator", func() {
+	var (
+		secret    []byte
+		validator auth.Validator
+	)
+
+	BeforeEach(func() {
+		secret = []byte("some-shared-secret")
+		validator = auth.NewHMACValidator(secret)
+	})
+
+	It("accepts a token scoped to the requested handle", func() {
+		token := signedToken(secret, "some-handle", time.Now().Add(time.Minute))
+
+		Expect(validator.Validate(token, "some-handle")).To(Succeed())
+	})
+
+	It("accepts a wildcard-scoped token for any handle", func() {
+		token := signedToken(secret, auth.AnyHandle, time.Now().Add(time.Minute))
+
+		Expect(validator.Validate(token, "some-handle")).To(Succeed())
+	})
+
+	It("rejects a token scoped to a different handle", func() {
+		token := signedToken(secret, "some-other-handle", time.Now().Add(time.Minute))
+
+		Expect(validator.Validate(token, "some-handle")).To(Equal(auth.ErrHandleMismatch))
+	})
+
+	It("rejects an expired token", func() {
+		token := signedToken(secret, "some-handle", time.Now().Add(-time.Minute))
+
+		Expect(validator.Validate(token, "some-handle")).To(Equal(auth.ErrTokenExpired))
+	})
+
+	It("rejects a token signed with the wrong secret", func() {
+		token := signedToken([]byte("wrong-secret"), "some-handle", time.Now().Add(time.Minute))
+
+		Expect(validator.Validate(token, "some-handle")).To(Equal(auth.ErrMalformedToken))
+	})
+
+	It("rejects an empty token", func() {
+		Expect(validator.Validate("", "some-handle")).To(Equal(auth.ErrNoToken))
+	})
+})
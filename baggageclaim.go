@@ -0,0 +1,60 @@
+package baggageclaim
+
+import (
+	"context"
+	"io"
+
+	"github.com/pivotal-golang/lager"
+)
+
+// Client is the interface implemented by the baggageclaim HTTP client,
+// used by Concourse workers and the ATC to manage volumes on a worker.
+type Client interface {
+	CreateVolume(logger lager.Logger, handle string, spec VolumeSpec) (Volume, error)
+	LookupVolume(logger lager.Logger, handle string) (Volume, bool, error)
+	ListVolumes(logger lager.Logger, properties VolumeProperties) (Volumes, error)
+	DestroyVolume(logger lager.Logger, handle string) error
+}
+
+// VolumeProperties are arbitrary key/value pairs a caller can attach to a
+// volume and later filter ListVolumes by.
+type VolumeProperties map[string]string
+
+// VolumeSpec describes how a new volume should be created.
+type VolumeSpec struct {
+	Strategy     Strategy
+	Properties   VolumeProperties
+	TTLInSeconds uint
+	Privileged   bool
+}
+
+// Volume is a handle to a directory on a worker, optionally backed by a
+// copy-on-write snapshot of another volume.
+type Volume interface {
+	Handle() string
+	Path() string
+
+	Properties() VolumeProperties
+	SetProperty(key string, value string) error
+
+	SetTTL(ttl uint) error
+	SetPrivileged(privileged bool) error
+
+	StreamIn(path string, tarStream io.Reader) error
+	StreamOut(path string) (io.ReadCloser, error)
+
+	// StreamOutOffset behaves like StreamOut, but resumes a deterministic
+	// tar stream at the given byte offset rather than starting over.
+	StreamOutOffset(path string, offset int64) (io.ReadCloser, error)
+
+	// ReplicateTo asks the server holding this volume to stream a copy of
+	// it directly to another baggageclaim at destination, landing it there
+	// as newHandle, so that server is pre-warmed before a build is
+	// scheduled on it. The caller's process is not in the data path.
+	ReplicateTo(ctx context.Context, destination string, newHandle string, privileged bool) error
+
+	Destroy() error
+}
+
+// Volumes is a collection of Volume, returned by Client.ListVolumes.
+type Volumes []Volume
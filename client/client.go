@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/auth"
+)
+
+// Client is the HTTP implementation of baggageclaim.Client. Every request
+// is authorized with a token minted by tokens, scoped to the handle being
+// operated on (or auth.AnyHandle for list/create), so a server started
+// with AuthConfig.Validator set will accept the request.
+type Client struct {
+	baseURL string
+	tokens  TokenSource
+
+	httpClient *http.Client
+}
+
+// New builds a Client talking to the baggageclaim server at baseURL,
+// authorizing every request with a token minted by tokens. Pass
+// NoopTokenSource{} for a server with no auth configured.
+func New(baseURL string, tokens TokenSource) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		tokens:     tokens,
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (c *Client) CreateVolume(logger lager.Logger, handle string, spec baggageclaim.VolumeSpec) (baggageclaim.Volume, error) {
+	strategy := spec.Strategy
+	if strategy == nil {
+		strategy = baggageclaim.EmptyStrategy{}
+	}
+
+	body, err := json.Marshal(baggageclaim.VolumeRequest{
+		Handle:       handle,
+		Strategy:     strategy.Encode(),
+		Properties:   spec.Properties,
+		Privileged:   spec.Privileged,
+		TTLInSeconds: spec.TTLInSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response baggageclaim.VolumeResponse
+	if err := c.doJSON("POST", "/volumes", auth.AnyHandle, bytes.NewReader(body), http.StatusCreated, &response); err != nil {
+		return nil, err
+	}
+
+	return c.volume(response), nil
+}
+
+func (c *Client) LookupVolume(logger lager.Logger, handle string) (baggageclaim.Volume, bool, error) {
+	resp, err := c.do("GET", "/volumes/"+handle, handle, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("lookup-volume: unexpected status %d", resp.StatusCode)
+	}
+
+	var response baggageclaim.VolumeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, false, err
+	}
+
+	return c.volume(response), true, nil
+}
+
+func (c *Client) ListVolumes(logger lager.Logger, properties baggageclaim.VolumeProperties) (baggageclaim.Volumes, error) {
+	var responses []baggageclaim.VolumeResponse
+	if err := c.doJSON("GET", "/volumes", auth.AnyHandle, nil, http.StatusOK, &responses); err != nil {
+		return nil, err
+	}
+
+	volumes := make(baggageclaim.Volumes, len(responses))
+	for i, response := range responses {
+		volumes[i] = c.volume(response)
+	}
+
+	return volumes, nil
+}
+
+func (c *Client) DestroyVolume(logger lager.Logger, handle string) error {
+	resp, err := c.do("DELETE", "/volumes/"+handle, handle, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("destroy-volume: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) do(method string, path string, handle string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.tokens.TokenFor(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) doJSON(method string, path string, handle string, body io.Reader, expectedStatus int, out interface{}) error {
+	resp, err := c.do(method, path, handle, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return fmt.Errorf("%s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) volume(response baggageclaim.VolumeResponse) baggageclaim.Volume {
+	return &clientVolume{
+		client: c,
+		handle: response.Handle,
+		path:   response.Path,
+	}
+}
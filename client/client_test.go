@@ -0,0 +1,126 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/client"
+)
+
+var _ = Describe("IntervalForTTL", func() {
+	It("has an upper bound of 1 minute", func() {
+		Expect(client.IntervalForTTL(500 * time.Second)).To(Equal(time.Minute))
+	})
+
+	Context("when the TTL is small", func() {
+		It("returns an interval that is half of the TTL", func() {
+			Expect(client.IntervalForTTL(5 * time.Second)).To(Equal(2500 * time.Millisecond))
+		})
+	})
+})
+
+var _ = Describe("Client", func() {
+	var (
+		server     *httptest.Server
+		bcClient   baggageclaim.Client
+		authHeader string
+	)
+
+	logger := lagertest.NewTestLogger("client")
+
+	BeforeEach(func() {
+		authHeader = ""
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/volumes", func(w http.ResponseWriter, r *http.Request) {
+			authHeader = r.Header.Get("Authorization")
+
+			var req baggageclaim.VolumeRequest
+			json.NewDecoder(r.Body).Decode(&req)
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(baggageclaim.VolumeResponse{
+				Handle: req.Handle,
+				Path:   "/volumes/" + req.Handle,
+			})
+		})
+		mux.HandleFunc("/volumes/some-handle", func(w http.ResponseWriter, r *http.Request) {
+			authHeader = r.Header.Get("Authorization")
+
+			switch r.Method {
+			case "GET":
+				json.NewEncoder(w).Encode(baggageclaim.VolumeResponse{
+					Handle: "some-handle",
+					Path:   "/volumes/some-handle",
+				})
+			case "DELETE":
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+		mux.HandleFunc("/volumes/missing-handle", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("with a token source configured", func() {
+		BeforeEach(func() {
+			bcClient = client.New(server.URL, client.NewHMACTokenSource([]byte("secret"), time.Minute))
+		})
+
+		It("authorizes CreateVolume with a bearer token", func() {
+			_, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{
+				Strategy: baggageclaim.EmptyStrategy{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authHeader).To(HavePrefix("Bearer "))
+		})
+	})
+
+	Context("with no auth configured", func() {
+		BeforeEach(func() {
+			bcClient = client.New(server.URL, client.NoopTokenSource{})
+		})
+
+		It("creates a volume and returns its handle and path", func() {
+			createdVolume, err := bcClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{
+				Strategy: baggageclaim.EmptyStrategy{},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdVolume.Handle()).To(Equal("some-handle"))
+			Expect(createdVolume.Path()).To(Equal("/volumes/some-handle"))
+			Expect(authHeader).To(Equal(""))
+		})
+
+		It("looks up an existing volume", func() {
+			foundVolume, found, err := bcClient.LookupVolume(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(foundVolume.Handle()).To(Equal("some-handle"))
+		})
+
+		It("reports a missing volume as not found, without an error", func() {
+			foundVolume, found, err := bcClient.LookupVolume(logger, "missing-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+			Expect(foundVolume).To(BeNil())
+		})
+
+		It("destroys a volume", func() {
+			err := bcClient.DestroyVolume(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package client
+
+import "time"
+
+// IntervalForTTL picks how often a volume's TTL should be heartbeated:
+// half the TTL, so a single missed heartbeat doesn't let it expire, but
+// at least once a minute regardless of how long the TTL is.
+func IntervalForTTL(ttl time.Duration) time.Duration {
+	interval := ttl / 2
+	if interval > time.Minute {
+		return time.Minute
+	}
+
+	return interval
+}
@@ -0,0 +1,25 @@
+package client
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// heartbeatTotal counts client-side volume heartbeats, so operators can
+// compare what the client believes it's sending against what the server
+// reports receiving. Incremented by clientVolume.SetTTL each time it tries
+// to reset a volume's TTL, labelled by whether the request succeeded.
+var heartbeatTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "baggageclaim_client_heartbeat_total",
+	Help: "Total number of volume heartbeats sent by the client, by outcome.",
+}, []string{"outcome"})
+
+func init() {
+	prometheus.MustRegister(heartbeatTotal)
+}
+
+func recordHeartbeat(err error) {
+	if err != nil {
+		heartbeatTotal.WithLabelValues("error").Inc()
+		return
+	}
+
+	heartbeatTotal.WithLabelValues("success").Inc()
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/auth"
+)
+
+// HTTPReplicationDestination implements volume.ReplicationDestination by
+// talking directly to another baggageclaim's HTTP API: it creates the
+// volume there (as a COW snapshot of parent when one is given) and then
+// streams its contents in, so a replicate request never routes the data
+// through the caller.
+type HTTPReplicationDestination struct {
+	BaseURL string
+	Tokens  TokenSource
+
+	HTTPClient *http.Client
+}
+
+// NewHTTPReplicationDestination builds a destination that authorizes each
+// request with a token from tokens, scoped to the handle it's created for
+// (or auth.AnyHandle for the POST /volumes that creates it).
+func NewHTTPReplicationDestination(baseURL string, tokens TokenSource) *HTTPReplicationDestination {
+	return &HTTPReplicationDestination{
+		BaseURL:    baseURL,
+		Tokens:     tokens,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// HasHandle reports whether the destination already holds handle, so the
+// caller can decide whether a `btrfs send -p` delta is usable.
+func (d *HTTPReplicationDestination) HasHandle(handle string) (bool, error) {
+	resp, err := d.do("GET", "/volumes/"+handle, handle, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// StreamIn creates an empty handle on the destination and extracts
+// tarStream into it via the ordinary stream-in endpoint.
+func (d *HTTPReplicationDestination) StreamIn(handle string, privileged bool, tarStream io.Reader) error {
+	if err := d.createVolume(handle, "", privileged); err != nil {
+		return err
+	}
+
+	resp, err := d.do("PUT", "/volumes/"+handle+"/stream-in", handle, tarStream)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("replication stream-in to %s failed with status %d", d.BaseURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ReceiveDelta creates handle on the destination as a COW snapshot of
+// parent, then hands sendStream to the dedicated receive-delta endpoint -
+// never stream-in - so the destination pipes it straight into
+// `btrfs receive` instead of trying to tar-extract a send stream.
+func (d *HTTPReplicationDestination) ReceiveDelta(handle string, parent string, privileged bool, sendStream io.Reader) error {
+	if err := d.createVolume(handle, parent, privileged); err != nil {
+		return err
+	}
+
+	resp, err := d.do("PUT", "/volumes/"+handle+"/receive-delta?parent="+parent, handle, sendStream)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("replication receive-delta to %s failed with status %d", d.BaseURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (d *HTTPReplicationDestination) createVolume(handle string, parent string, privileged bool) error {
+	var strategy json.RawMessage
+	if parent != "" {
+		strategy, _ = json.Marshal(struct {
+			Type   string `json:"type"`
+			Volume string `json:"volume"`
+		}{
+			Type:   string(baggageclaim.COWStrategyName),
+			Volume: parent,
+		})
+	} else {
+		strategy, _ = json.Marshal(struct {
+			Type string `json:"type"`
+		}{
+			Type: string(baggageclaim.EmptyStrategyName),
+		})
+	}
+
+	body, err := json.Marshal(struct {
+		Handle     string          `json:"handle"`
+		Strategy   json.RawMessage `json:"strategy"`
+		Privileged bool            `json:"privileged"`
+	}{
+		Handle:     handle,
+		Strategy:   strategy,
+		Privileged: privileged,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.do("POST", "/volumes", auth.AnyHandle, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("replication create-volume on %s failed with status %d", d.BaseURL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (d *HTTPReplicationDestination) do(method string, path string, handle string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, d.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := d.Tokens.TokenFor(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return d.HTTPClient.Do(req)
+}
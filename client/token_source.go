@@ -0,0 +1,49 @@
+package client
+
+import (
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/concourse/baggageclaim/auth"
+)
+
+// TokenSource mints a bearer token authorizing requests against the given
+// volume handle (or auth.AnyHandle for list/create requests). Implementations
+// are free to cache and reuse tokens as long as they remain unexpired.
+type TokenSource interface {
+	TokenFor(handle string) (string, error)
+}
+
+// NoopTokenSource is used when the server has no auth configured; it mints
+// no token, so requests are sent without an Authorization header.
+type NoopTokenSource struct{}
+
+func (NoopTokenSource) TokenFor(string) (string, error) {
+	return "", nil
+}
+
+type hmacTokenSource struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewHMACTokenSource returns a TokenSource that signs short-lived HS256
+// tokens, each scoped to a single handle, valid for the given ttl.
+func NewHMACTokenSource(secret []byte, ttl time.Duration) TokenSource {
+	return hmacTokenSource{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+func (s hmacTokenSource) TokenFor(handle string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Handle: handle,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(s.ttl).Unix(),
+		},
+	})
+
+	return token.SignedString(s.secret)
+}
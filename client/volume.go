@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/concourse/baggageclaim"
+)
+
+// clientVolume is the HTTP implementation of baggageclaim.Volume returned
+// by Client; every method round-trips to the server that created it.
+type clientVolume struct {
+	client *Client
+	handle string
+	path   string
+}
+
+func (v *clientVolume) Handle() string { return v.handle }
+func (v *clientVolume) Path() string   { return v.path }
+
+func (v *clientVolume) Properties() baggageclaim.VolumeProperties {
+	var response baggageclaim.VolumeResponse
+	if err := v.client.doJSON("GET", "/volumes/"+v.handle, v.handle, nil, http.StatusOK, &response); err != nil {
+		return baggageclaim.VolumeProperties{}
+	}
+
+	return response.Properties
+}
+
+func (v *clientVolume) SetProperty(key string, value string) error {
+	body, err := json.Marshal(baggageclaim.PropertyRequest{Value: value})
+	if err != nil {
+		return err
+	}
+
+	return v.put("/volumes/"+v.handle+"/properties/"+key, bytes.NewReader(body))
+}
+
+func (v *clientVolume) SetTTL(ttl uint) error {
+	body, err := json.Marshal(baggageclaim.TTLRequest{Value: ttl})
+	if err != nil {
+		return err
+	}
+
+	err = v.put("/volumes/"+v.handle+"/ttl", bytes.NewReader(body))
+	recordHeartbeat(err)
+	return err
+}
+
+func (v *clientVolume) SetPrivileged(privileged bool) error {
+	body, err := json.Marshal(struct {
+		Value bool `json:"value"`
+	}{Value: privileged})
+	if err != nil {
+		return err
+	}
+
+	return v.put("/volumes/"+v.handle+"/privileged", bytes.NewReader(body))
+}
+
+func (v *clientVolume) put(path string, body io.Reader) error {
+	resp, err := v.client.do("PUT", path, v.handle, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("PUT %s: unexpected status %d", path, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (v *clientVolume) StreamIn(path string, tarStream io.Reader) error {
+	resp, err := v.client.do("PUT", "/volumes/"+v.handle+"/stream-in?path="+path, v.handle, tarStream)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("stream-in: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (v *clientVolume) StreamOut(path string) (io.ReadCloser, error) {
+	resp, err := v.client.do("GET", "/volumes/"+v.handle+"/stream-out?path="+path, v.handle, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("stream-out: unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func (v *clientVolume) StreamOutOffset(path string, offset int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", v.client.baseURL+"/volumes/"+v.handle+"/stream-out?path="+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+
+	token, err := v.client.tokens.TokenFor(v.handle)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := v.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("stream-out: unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func (v *clientVolume) ReplicateTo(ctx context.Context, destination string, newHandle string, privileged bool) error {
+	body, err := json.Marshal(baggageclaim.ReplicationRequest{
+		Destination: destination,
+		Handle:      newHandle,
+		Privileged:  privileged,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", v.client.baseURL+"/volumes/"+v.handle+"/replicate", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	token, err := v.client.tokens.TokenFor(v.handle)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := v.client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("replicate: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (v *clientVolume) Destroy() error {
+	resp, err := v.client.do("DELETE", "/volumes/"+v.handle, v.handle, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("destroy-volume: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
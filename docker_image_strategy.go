@@ -0,0 +1,38 @@
+package baggageclaim
+
+import "encoding/json"
+
+// DockerImageStrategy creates a volume whose contents are the flattened
+// rootfs of an OCI/Docker image, resolved against a v2 registry.
+type DockerImageStrategy struct {
+	Registry   string        `json:"registry"`
+	Repository string        `json:"repository"`
+	Reference  string        `json:"reference"`
+	Auth       *RegistryAuth `json:"auth,omitempty"`
+}
+
+// RegistryAuth carries credentials for the registry's bearer-token flow
+// (a GET against the challenge's token realm, e.g. /v2/token) or, if Token
+// is set directly, a pre-minted
+// bearer token to use as-is.
+type RegistryAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+func (s DockerImageStrategy) Encode() *json.RawMessage {
+	return encode(struct {
+		Type       string        `json:"type"`
+		Registry   string        `json:"registry"`
+		Repository string        `json:"repository"`
+		Reference  string        `json:"reference"`
+		Auth       *RegistryAuth `json:"auth,omitempty"`
+	}{
+		Type:       string(DockerImageStrategyName),
+		Registry:   s.Registry,
+		Repository: s.Repository,
+		Reference:  s.Reference,
+		Auth:       s.Auth,
+	})
+}
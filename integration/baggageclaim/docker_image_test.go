@@ -0,0 +1,64 @@
+package integration_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim"
+)
+
+// These tests exercise DockerImageStrategy end-to-end against a real
+// registry, so they only run when BAGGAGECLAIM_TEST_REGISTRY points at one,
+// e.g. a `registry:2` container started alongside the test run.
+var _ = Describe("Creating a volume from a Docker image", func() {
+	var (
+		runner *BaggageClaimRunner
+		client baggageclaim.Client
+
+		registryHost string
+	)
+
+	BeforeEach(func() {
+		registryHost = os.Getenv("BAGGAGECLAIM_TEST_REGISTRY")
+		if registryHost == "" {
+			Skip("BAGGAGECLAIM_TEST_REGISTRY is not set")
+			return
+		}
+
+		runner = NewRunner(baggageClaimPath)
+		runner.Start()
+
+		client = runner.Client()
+	})
+
+	AfterEach(func() {
+		if registryHost == "" {
+			return
+		}
+
+		runner.Stop()
+		runner.Cleanup()
+	})
+
+	It("materializes the image's flattened rootfs into the volume", func() {
+		createdVolume, err := client.CreateVolume(logger, "image-handle", baggageclaim.VolumeSpec{
+			Strategy: baggageclaim.DockerImageStrategy{
+				Registry:   registryHost,
+				Repository: "library/busybox",
+				Reference:  "latest",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := ioutil.ReadDir(createdVolume.Path())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).NotTo(BeEmpty())
+
+		_, err = os.Stat(filepath.Join(createdVolume.Path(), "bin", "busybox"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
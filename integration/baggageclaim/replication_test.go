@@ -0,0 +1,102 @@
+package integration_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/uidgid"
+)
+
+// These tests exercise Volume.ReplicateTo end-to-end between two real
+// baggageclaim servers, so each volume's uid/gid mapping has to survive
+// both the source server's uid-mapped StreamOut and the destination's
+// uid-mapped StreamIn.
+var _ = Describe("Replicating a volume to another baggageclaim", func() {
+	var (
+		source      *BaggageClaimRunner
+		destination *BaggageClaimRunner
+
+		sourceClient      baggageclaim.Client
+		destinationClient baggageclaim.Client
+
+		baseVolume   baggageclaim.Volume
+		dataFilename string
+	)
+
+	maxUID := uidgid.MustGetMaxValidUID()
+	maxGID := uidgid.MustGetMaxValidGID()
+
+	BeforeEach(func() {
+		source = NewRunner(baggageClaimPath)
+		source.Start()
+		sourceClient = source.Client()
+
+		destination = NewRunner(baggageClaimPath)
+		destination.Start()
+		destinationClient = destination.Client()
+
+		var err error
+		baseVolume, err = sourceClient.CreateVolume(logger, "some-handle", baggageclaim.VolumeSpec{})
+		Expect(err).NotTo(HaveOccurred())
+
+		dataFilename = "data"
+		err = ioutil.WriteFile(filepath.Join(baseVolume.Path(), dataFilename), []byte("hello"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		source.Stop()
+		source.Cleanup()
+		destination.Stop()
+		destination.Cleanup()
+	})
+
+	replicatedOwner := func(handle string) (uint32, uint32) {
+		replicated, found, err := destinationClient.LookupVolume(logger, handle)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		stat, err := os.Stat(filepath.Join(replicated.Path(), dataFilename))
+		Expect(err).NotTo(HaveOccurred())
+
+		sysStat := stat.Sys().(*syscall.Stat_t)
+		return sysStat.Uid, sysStat.Gid
+	}
+
+	Context("replicating an unprivileged volume", func() {
+		BeforeEach(func() {
+			var err error
+			baseVolume, err = sourceClient.CreateVolume(logger, "unprivileged-handle", baggageclaim.VolumeSpec{
+				Strategy: baggageclaim.COWStrategy{Parent: baseVolume},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("preserves the host-namespace uid mapping on the destination", func() {
+			err := baseVolume.ReplicateTo(context.Background(), destination.ApiURL(), "replicated-handle", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			uid, gid := replicatedOwner("replicated-handle")
+			Expect(uid).To(Equal(uint32(maxUID)))
+			Expect(gid).To(Equal(uint32(maxGID)))
+		})
+	})
+
+	Context("replicating to a privileged destination volume", func() {
+		It("maps uid 0 to uid 0", func() {
+			err := baseVolume.ReplicateTo(context.Background(), destination.ApiURL(), "replicated-handle", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			uid, gid := replicatedOwner("replicated-handle")
+			Expect(uid).To(Equal(uint32(0)))
+			Expect(gid).To(Equal(uint32(0)))
+		})
+	})
+})
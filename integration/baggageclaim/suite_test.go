@@ -0,0 +1,47 @@
+package integration_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+	"github.com/pivotal-golang/lager/lagertest"
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Integration Suite")
+}
+
+var (
+	baggageClaimPath string
+	logger           = lagertest.NewTestLogger("integration")
+)
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	path, err := gexec.Build("github.com/concourse/baggageclaim/cmd/baggageclaim")
+	Expect(err).NotTo(HaveOccurred())
+
+	return []byte(path)
+}, func(path []byte) {
+	baggageClaimPath = string(path)
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	gexec.CleanupBuildArtifacts()
+})
+
+var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+// randSeq returns a random alphabetic string of length n, used to name
+// scratch files/links that shouldn't collide across parallel test nodes.
+func randSeq(n int) string {
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = letters[rand.Intn(len(letters))]
+	}
+
+	return string(b)
+}
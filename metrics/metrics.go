@@ -0,0 +1,88 @@
+// Package metrics registers the Prometheus collectors baggageclaim
+// exposes on /metrics and the middleware/helpers that feed them.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	RequestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "baggageclaim_request_total",
+		Help: "Total number of API requests handled, by endpoint, method and status code.",
+	}, []string{"endpoint", "method", "status"})
+
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "baggageclaim_request_duration_seconds",
+		Help: "Time taken to handle an API request, by endpoint.",
+	}, []string{"endpoint"})
+
+	// Volumes and VolumeBytes are deliberately NOT registered below: they're
+	// only ever exposed through NewVolumeCountCollector/NewVolumeBytesCollector,
+	// which query their source at scrape time and then report through these
+	// same GaugeVecs. Registering both the raw vec and its collector would
+	// describe the same metric twice and panic MustRegister's duplicate check.
+	Volumes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "baggageclaim_volumes",
+		Help: "Number of volumes currently on the worker, by privileged status.",
+	}, []string{"privileged"})
+
+	VolumeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "baggageclaim_volume_bytes",
+		Help: "Size on disk of each volume, by handle.",
+	}, []string{"handle"})
+
+	StreamBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "baggageclaim_stream_bytes_total",
+		Help: "Total bytes streamed in or out of volumes.",
+	}, []string{"direction"})
+
+	TTLExpirationsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "baggageclaim_ttl_expirations_total",
+		Help: "Total number of volumes reaped due to their TTL expiring.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		RequestTotal,
+		RequestDuration,
+		StreamBytesTotal,
+		TTLExpirationsTotal,
+	)
+}
+
+// Handler serves the registered collectors in the Prometheus exposition
+// format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// WrapHandler instruments handler, incrementing RequestTotal and
+// observing RequestDuration under the given endpoint label.
+func WrapHandler(endpoint string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(recorder, r)
+
+		RequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+		RequestTotal.WithLabelValues(endpoint, r.Method, strconv.Itoa(recorder.status)).Inc()
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/concourse/baggageclaim/metrics"
+)
+
+func counterValue(vec *prometheus.CounterVec, labels prometheus.Labels) float64 {
+	m := &dto.Metric{}
+	vec.With(labels).(prometheus.Metric).Write(m)
+	return m.GetCounter().GetValue()
+}
+
+var _ = Describe("WrapHandler", func() {
+	It("records a request_total entry with the response status", func() {
+		handler := metrics.WrapHandler("/volumes", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest("POST", "/volumes", nil)
+		recorder := httptest.NewRecorder()
+
+		before := counterValue(metrics.RequestTotal, prometheus.Labels{
+			"endpoint": "/volumes",
+			"method":   "POST",
+			"status":   "201",
+		})
+
+		handler.ServeHTTP(recorder, req)
+
+		after := counterValue(metrics.RequestTotal, prometheus.Labels{
+			"endpoint": "/volumes",
+			"method":   "POST",
+			"status":   "201",
+		})
+
+		Expect(after).To(Equal(before + 1))
+		Expect(recorder.Code).To(Equal(http.StatusCreated))
+	})
+})
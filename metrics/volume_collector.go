@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VolumeCounter is satisfied by the volume repository: it reports how
+// many privileged and unprivileged volumes currently exist, so the
+// baggageclaim_volumes gauge can be scraped on demand rather than kept in
+// sync on every create/destroy.
+type VolumeCounter interface {
+	CountVolumes() (privileged int, unprivileged int, err error)
+}
+
+type volumeCountCollector struct {
+	counter VolumeCounter
+}
+
+// NewVolumeCountCollector returns a prometheus.Collector that reports
+// baggageclaim_volumes{privileged} by querying counter each time it is
+// scraped.
+func NewVolumeCountCollector(counter VolumeCounter) prometheus.Collector {
+	return volumeCountCollector{counter: counter}
+}
+
+func (c volumeCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	Volumes.Describe(ch)
+}
+
+func (c volumeCountCollector) Collect(ch chan<- prometheus.Metric) {
+	privileged, unprivileged, err := c.counter.CountVolumes()
+	if err != nil {
+		return
+	}
+
+	Volumes.WithLabelValues("true").Set(float64(privileged))
+	Volumes.WithLabelValues("false").Set(float64(unprivileged))
+
+	Volumes.Collect(ch)
+}
+
+// VolumeSizer is satisfied by the volume repository: it reports the
+// on-disk size of each volume it holds (e.g. via `btrfs filesystem du`),
+// so baggageclaim_volume_bytes can be scraped on demand rather than kept
+// in sync on every write.
+type VolumeSizer interface {
+	VolumeSizes() (map[string]int64, error)
+}
+
+type volumeBytesCollector struct {
+	sizer VolumeSizer
+
+	// mu serializes Collect: unlike Volumes (fixed "true"/"false" label
+	// values), VolumeBytes is Reset and repopulated with a varying set of
+	// handles each scrape, so two scrapes running at once could otherwise
+	// observe each other's half-populated gauge.
+	mu *sync.Mutex
+}
+
+// NewVolumeBytesCollector returns a prometheus.Collector that reports
+// baggageclaim_volume_bytes{handle} by querying sizer each time it is
+// scraped.
+func NewVolumeBytesCollector(sizer VolumeSizer) prometheus.Collector {
+	return volumeBytesCollector{sizer: sizer, mu: &sync.Mutex{}}
+}
+
+func (c volumeBytesCollector) Describe(ch chan<- *prometheus.Desc) {
+	VolumeBytes.Describe(ch)
+}
+
+func (c volumeBytesCollector) Collect(ch chan<- prometheus.Metric) {
+	sizes, err := c.sizer.VolumeSizes()
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	VolumeBytes.Reset()
+	for handle, size := range sizes {
+		VolumeBytes.WithLabelValues(handle).Set(float64(size))
+	}
+
+	VolumeBytes.Collect(ch)
+}
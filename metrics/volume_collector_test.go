@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/concourse/baggageclaim/metrics"
+)
+
+type fakeVolumeSizer struct {
+	sizes map[string]int64
+	err   error
+}
+
+func (f fakeVolumeSizer) VolumeSizes() (map[string]int64, error) {
+	return f.sizes, f.err
+}
+
+type fakeVolumeCounter struct{}
+
+func (fakeVolumeCounter) CountVolumes() (int, int, error) { return 0, 0, nil }
+
+func gaugeValue(vec *prometheus.GaugeVec, labels prometheus.Labels) float64 {
+	m := &dto.Metric{}
+	vec.With(labels).(prometheus.Metric).Write(m)
+	return m.GetGauge().GetValue()
+}
+
+var _ = Describe("NewVolumeBytesCollector", func() {
+	It("reports the sizer's sizes under their handle label when scraped", func() {
+		collector := metrics.NewVolumeBytesCollector(fakeVolumeSizer{
+			sizes: map[string]int64{"some-handle": 1024},
+		})
+
+		ch := make(chan prometheus.Metric, 1)
+		collector.Collect(ch)
+		close(ch)
+
+		for range ch {
+		}
+
+		Expect(gaugeValue(metrics.VolumeBytes, prometheus.Labels{"handle": "some-handle"})).To(Equal(float64(1024)))
+	})
+
+	It("registers cleanly against the package's default registry", func() {
+		// Volumes/VolumeBytes must not already be registered directly (see
+		// metrics.go's init) or this duplicate-descriptor check panics.
+		Expect(prometheus.Register(metrics.NewVolumeBytesCollector(fakeVolumeSizer{}))).To(Succeed())
+		Expect(prometheus.Register(metrics.NewVolumeCountCollector(fakeVolumeCounter{}))).To(Succeed())
+	})
+})
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseBearerChallenge parses a `Www-Authenticate: Bearer realm="...",
+// service="...", scope="..."` header into the realm to request a token
+// from and the query parameters to request it with.
+func parseBearerChallenge(header string) (string, url.Values, error) {
+	matches := challengeParamPattern.FindAllStringSubmatch(header, -1)
+
+	params := url.Values{}
+	var realm string
+
+	for _, match := range matches {
+		key, value := match[1], match[2]
+
+		if key == "realm" {
+			realm = value
+			continue
+		}
+
+		params.Set(key, value)
+	}
+
+	if realm == "" {
+		return "", nil, fmt.Errorf("registry: no realm in challenge: %q", header)
+	}
+
+	return realm, params, nil
+}
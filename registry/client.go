@@ -0,0 +1,172 @@
+// Package registry is a small Docker Registry v2 HTTP client: just enough
+// to resolve a manifest and fetch the layer blobs it references, which is
+// all baggageclaim's DockerImageStrategy needs.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/concourse/baggageclaim"
+)
+
+const manifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+// Client talks to a single repository on a v2 registry.
+type Client struct {
+	httpClient *http.Client
+
+	baseURL    string
+	repository string
+	auth       *baggageclaim.RegistryAuth
+}
+
+// New builds a Client for the given registry host (e.g. "registry-1.docker.io",
+// or "http://localhost:5000" for a local/insecure registry used in tests)
+// and repository (e.g. "library/busybox").
+func New(registryHost string, repository string, auth *baggageclaim.RegistryAuth) *Client {
+	baseURL := registryHost
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	return &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURL,
+		repository: repository,
+		auth:       auth,
+	}
+}
+
+// ResolveManifest fetches and decodes the manifest for the given tag or
+// digest reference.
+func (c *Client) ResolveManifest(reference string) (Manifest, error) {
+	url := fmt.Sprintf("%s/v2/%s/manifests/%s", c.baseURL, c.repository, reference)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	req.Header.Set("Accept", manifestMediaType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Manifest{}, fmt.Errorf("registry: unexpected status resolving manifest: %s", resp.Status)
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// FetchBlob streams the content-addressed blob for the given digest
+// (e.g. "sha256:abcd..."). The caller is responsible for verifying the
+// digest of what it reads and closing the returned reader.
+func (c *Client) FetchBlob(digest string) (io.ReadCloser, error) {
+	url := fmt.Sprintf("%s/v2/%s/blobs/%s", c.baseURL, c.repository, digest)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry: unexpected status fetching blob %s: %s", digest, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// do issues req, authenticating against the registry's bearer-token
+// endpoint and retrying once if the registry challenges with a 401.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if token := c.staticToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("Www-Authenticate")
+	resp.Body.Close()
+
+	token, err := c.fetchBearerToken(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return c.httpClient.Do(req)
+}
+
+func (c *Client) staticToken() string {
+	if c.auth == nil {
+		return ""
+	}
+
+	return c.auth.Token
+}
+
+// fetchBearerToken exchanges the registry's "Bearer realm=... service=...
+// scope=..." challenge for a token against the realm's /v2/token-style
+// endpoint, using basic auth if credentials were provided.
+func (c *Client) fetchBearerToken(challenge string) (string, error) {
+	realm, params, err := parseBearerChallenge(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("GET", realm+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if c.auth != nil && c.auth.Username != "" {
+		req.SetBasicAuth(c.auth.Username, c.auth.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry: token request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	return body.Token, nil
+}
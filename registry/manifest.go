@@ -0,0 +1,18 @@
+package registry
+
+// Manifest is the subset of a Docker Registry v2 (schema 2) image manifest
+// baggageclaim needs in order to pull and flatten layers.
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+// Descriptor addresses a single content-addressable blob within a
+// repository.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
@@ -6,8 +6,10 @@ import (
 )
 
 type VolumeRequest struct {
+	Handle       string           `json:"handle"`
 	Strategy     *json.RawMessage `json:"strategy"`
 	Properties   VolumeProperties `json:"properties"`
+	Privileged   bool             `json:"privileged,omitempty"`
 	TTLInSeconds uint             `json:"ttl,omitempty"`
 }
 
@@ -26,3 +28,12 @@ type PropertyRequest struct {
 type TTLRequest struct {
 	Value uint `json:"value"`
 }
+
+// ReplicationRequest is the body of POST /volumes/:handle/replicate. It
+// asks the server holding :handle to push a copy of it to another
+// baggageclaim at Destination, landing it there as Handle.
+type ReplicationRequest struct {
+	Destination string `json:"destination"`
+	Handle      string `json:"handle"`
+	Privileged  bool   `json:"privileged"`
+}
@@ -0,0 +1,56 @@
+package baggageclaim
+
+import "encoding/json"
+
+// StrategyName identifies which Strategy a VolumeRequest's raw strategy
+// payload should be decoded as.
+type StrategyName string
+
+const (
+	EmptyStrategyName       StrategyName = "empty"
+	COWStrategyName         StrategyName = "cow"
+	DockerImageStrategyName StrategyName = "docker-image"
+)
+
+// Strategy describes how the contents of a new volume should be
+// populated. It is encoded as the `strategy` field of a VolumeRequest.
+type Strategy interface {
+	Encode() *json.RawMessage
+}
+
+func encode(v interface{}) *json.RawMessage {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	raw := json.RawMessage(payload)
+	return &raw
+}
+
+// EmptyStrategy creates a volume with no initial contents.
+type EmptyStrategy struct{}
+
+func (EmptyStrategy) Encode() *json.RawMessage {
+	return encode(struct {
+		Type string `json:"type"`
+	}{
+		Type: string(EmptyStrategyName),
+	})
+}
+
+// COWStrategy creates a volume whose contents are a copy-on-write snapshot
+// of Parent.
+type COWStrategy struct {
+	Parent Volume
+}
+
+func (s COWStrategy) Encode() *json.RawMessage {
+	return encode(struct {
+		Type   string `json:"type"`
+		Volume string `json:"volume"`
+	}{
+		Type:   string(COWStrategyName),
+		Volume: s.Parent.Handle(),
+	})
+}
@@ -0,0 +1,96 @@
+// Package uidgid maps uid/gid 0 (root) in a volume's contents to the
+// host's overflow uid/gid, so that unprivileged volumes never
+// contain a file actually owned by host root.
+package uidgid
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+const (
+	overflowUIDPath = "/proc/sys/kernel/overflowuid"
+	overflowGIDPath = "/proc/sys/kernel/overflowgid"
+
+	fallbackOverflowID = 65534
+)
+
+// MustGetMaxValidUID returns the host's overflow uid (the value the
+// kernel maps unmappable uids to). If it cannot be read, 65534 is
+// returned; if its contents are not a number, it panics.
+func MustGetMaxValidUID() int {
+	return mustReadOverflowID(overflowUIDPath)
+}
+
+// MustGetMaxValidGID returns the host's overflow gid.
+func MustGetMaxValidGID() int {
+	return mustReadOverflowID(overflowGIDPath)
+}
+
+func mustReadOverflowID(path string) int {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fallbackOverflowID
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		panic(fmt.Sprintf("uidgid: malformed overflow id in %s: %s", path, err))
+	}
+
+	return id
+}
+
+// Translator remaps uid/gid 0 to the namespaced id and back, used when
+// writing into and reading out of an unprivileged volume.
+type Translator interface {
+	ToNamespace(uid, gid int) (int, int)
+	FromNamespace(uid, gid int) (int, int)
+}
+
+type namespacingTranslator struct {
+	maxUID int
+	maxGID int
+}
+
+// NewNamespacingTranslator returns a Translator that maps uid/gid 0 to
+// the host's overflow uid/gid, as used for unprivileged volumes.
+func NewNamespacingTranslator() Translator {
+	return namespacingTranslator{
+		maxUID: MustGetMaxValidUID(),
+		maxGID: MustGetMaxValidGID(),
+	}
+}
+
+func (t namespacingTranslator) ToNamespace(uid, gid int) (int, int) {
+	if uid == 0 {
+		uid = t.maxUID
+	}
+
+	if gid == 0 {
+		gid = t.maxGID
+	}
+
+	return uid, gid
+}
+
+func (t namespacingTranslator) FromNamespace(uid, gid int) (int, int) {
+	if uid == t.maxUID {
+		uid = 0
+	}
+
+	if gid == t.maxGID {
+		gid = 0
+	}
+
+	return uid, gid
+}
+
+// NoopTranslator is used for privileged volumes, where uid/gid 0 is left
+// unmapped.
+type NoopTranslator struct{}
+
+func (NoopTranslator) ToNamespace(uid, gid int) (int, int)   { return uid, gid }
+func (NoopTranslator) FromNamespace(uid, gid int) (int, int) { return uid, gid }
@@ -0,0 +1,261 @@
+package volume
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/registry"
+	"github.com/concourse/baggageclaim/uidgid"
+)
+
+const whiteoutPrefix = ".wh."
+const opaqueWhiteout = ".wh..wh..opq"
+
+// BlobCache dedupes layer downloads across volumes: a layer already
+// fetched for one image pull is reused by digest rather than re-pulled.
+type BlobCache struct {
+	dir string
+
+	mu     sync.Mutex
+	digest map[string]*sync.Mutex
+}
+
+// NewBlobCache returns a BlobCache that stores downloaded layers under
+// dir, shared across all DockerImageStrategy materializations.
+func NewBlobCache(dir string) *BlobCache {
+	return &BlobCache{
+		dir:    dir,
+		digest: map[string]*sync.Mutex{},
+	}
+}
+
+func (c *BlobCache) path(digest string) string {
+	return filepath.Join(c.dir, strings.Replace(digest, ":", "_", 1))
+}
+
+// lockDigest serializes Fetch calls for the same digest, so layers that
+// share a digest - common across a manifest, or across concurrent pulls -
+// download it once instead of racing on the same cache entry.
+func (c *BlobCache) lockDigest(digest string) func() {
+	c.mu.Lock()
+	l, ok := c.digest[digest]
+	if !ok {
+		l = &sync.Mutex{}
+		c.digest[digest] = l
+	}
+	c.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// Fetch returns the local path to digest's blob, downloading and
+// verifying it via client first if it isn't already cached.
+func (c *BlobCache) Fetch(client *registry.Client, digest string) (string, error) {
+	unlock := c.lockDigest(digest)
+	defer unlock()
+
+	path := c.path(digest)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempFile(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+
+	blob, err := client.FetchBlob(digest)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+	defer blob.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), blob); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	tmp.Close()
+
+	if got := "sha256:" + hex.EncodeToString(hasher.Sum(nil)); got != digest {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("docker-image: digest mismatch: expected %s, got %s", digest, got)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// MaterializeDockerImage resolves strategy's manifest, downloads its
+// layers (concurrently, deduped by the shared cache) and applies them in
+// order into destination, honoring whiteout files. When privileged is
+// false, files are namespaced so uid/gid 0 lands at MAX_UID/MAX_GID.
+func MaterializeDockerImage(strategy baggageclaim.DockerImageStrategy, cache *BlobCache, destination string, privileged bool) error {
+	client := registry.New(strategy.Registry, strategy.Repository, strategy.Auth)
+
+	manifest, err := client.ResolveManifest(strategy.Reference)
+	if err != nil {
+		return err
+	}
+
+	layerPaths := make([]string, len(manifest.Layers))
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(manifest.Layers))
+
+	for i, layer := range manifest.Layers {
+		wg.Add(1)
+		go func(i int, digest string) {
+			defer wg.Done()
+
+			path, err := cache.Fetch(client, digest)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			layerPaths[i] = path
+		}(i, layer.Digest)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	translator := uidgid.Translator(uidgid.NoopTranslator{})
+	if !privileged {
+		translator = uidgid.NewNamespacingTranslator()
+	}
+
+	for _, path := range layerPaths {
+		if err := applyLayer(path, destination, translator); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyLayer(layerPath string, destination string, translator uidgid.Translator) error {
+	f, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dir, base := filepath.Split(header.Name)
+
+		if base == opaqueWhiteout {
+			if err := clearDirContents(filepath.Join(destination, dir)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			target := filepath.Join(destination, dir, strings.TrimPrefix(base, whiteoutPrefix))
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := extractEntry(tr, header, destination, translator); err != nil {
+			return err
+		}
+	}
+}
+
+func clearDirContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractEntry(tr *tar.Reader, header *tar.Header, destination string, translator uidgid.Translator) error {
+	target := filepath.Join(destination, header.Name)
+	uid, gid := translator.ToNamespace(header.Uid, header.Gid)
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+			return err
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+
+		out.Close()
+	case tar.TypeSymlink:
+		os.Remove(target)
+		if err := os.Symlink(header.Linkname, target); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	return os.Lchown(target, uid, gid)
+}
@@ -0,0 +1,161 @@
+package volume_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim"
+	"github.com/concourse/baggageclaim/registry"
+	"github.com/concourse/baggageclaim/volume"
+)
+
+func layerWithEntries(entries map[string]string) ([]byte, string) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+
+	for name, contents := range entries {
+		tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(contents)),
+		})
+		tw.Write([]byte(contents))
+	}
+
+	tw.Close()
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), "sha256:" + hex.EncodeToString(sum[:])
+}
+
+var _ = Describe("MaterializeDockerImage", func() {
+	var (
+		server      *httptest.Server
+		destination string
+		cacheDir    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		destination, err = ioutil.TempDir("", "docker-image-destination")
+		Expect(err).NotTo(HaveOccurred())
+
+		cacheDir, err = ioutil.TempDir("", "docker-image-cache")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(destination)
+		os.RemoveAll(cacheDir)
+	})
+
+	It("applies layers in order, honoring whiteouts", func() {
+		baseLayer, baseDigest := layerWithEntries(map[string]string{
+			"foo.txt":    "original",
+			"keep.txt":   "keep-me",
+			"remove.txt": "remove-me",
+		})
+
+		topLayer, topDigest := layerWithEntries(map[string]string{
+			"foo.txt":        "overwritten",
+			".wh.remove.txt": "",
+		})
+
+		manifest := registry.Manifest{
+			SchemaVersion: 2,
+			Layers: []registry.Descriptor{
+				{Digest: baseDigest, Size: int64(len(baseLayer))},
+				{Digest: topDigest, Size: int64(len(topLayer))},
+			},
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/some/repo/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(manifest)
+		})
+		mux.HandleFunc(fmt.Sprintf("/v2/some/repo/blobs/%s", baseDigest), func(w http.ResponseWriter, r *http.Request) {
+			w.Write(baseLayer)
+		})
+		mux.HandleFunc(fmt.Sprintf("/v2/some/repo/blobs/%s", topDigest), func(w http.ResponseWriter, r *http.Request) {
+			w.Write(topLayer)
+		})
+
+		server = httptest.NewServer(mux)
+
+		strategy := baggageclaim.DockerImageStrategy{
+			Registry:   server.URL,
+			Repository: "some/repo",
+			Reference:  "latest",
+		}
+
+		err := volume.MaterializeDockerImage(strategy, volume.NewBlobCache(cacheDir), destination, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		foo, err := ioutil.ReadFile(filepath.Join(destination, "foo.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(foo)).To(Equal("overwritten"))
+
+		keep, err := ioutil.ReadFile(filepath.Join(destination, "keep.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(keep)).To(Equal("keep-me"))
+
+		_, err = os.Stat(filepath.Join(destination, "remove.txt"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("fetches a layer referenced more than once in a manifest only once", func() {
+		sharedLayer, sharedDigest := layerWithEntries(map[string]string{
+			"foo.txt": "shared",
+		})
+
+		var fetches int32
+
+		manifest := registry.Manifest{
+			SchemaVersion: 2,
+			Layers: []registry.Descriptor{
+				{Digest: sharedDigest, Size: int64(len(sharedLayer))},
+				{Digest: sharedDigest, Size: int64(len(sharedLayer))},
+			},
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v2/some/repo/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(manifest)
+		})
+		mux.HandleFunc(fmt.Sprintf("/v2/some/repo/blobs/%s", sharedDigest), func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&fetches, 1)
+			w.Write(sharedLayer)
+		})
+
+		server = httptest.NewServer(mux)
+
+		strategy := baggageclaim.DockerImageStrategy{
+			Registry:   server.URL,
+			Repository: "some/repo",
+			Reference:  "latest",
+		}
+
+		err := volume.MaterializeDockerImage(strategy, volume.NewBlobCache(cacheDir), destination, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(atomic.LoadInt32(&fetches)).To(Equal(int32(1)))
+
+		foo, err := ioutil.ReadFile(filepath.Join(destination, "foo.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(foo)).To(Equal("shared"))
+	})
+})
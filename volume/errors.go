@@ -0,0 +1,5 @@
+package volume
+
+import "errors"
+
+var ErrVolumeDoesNotExist = errors.New("volume does not exist")
@@ -0,0 +1,82 @@
+package volume
+
+import "io"
+
+// ReplicationDestination is how a Replicator reaches another baggageclaim:
+// it can report whether a handle is already present there, and exposes two
+// distinct receive paths so the destination never has to guess what kind
+// of stream it's being handed. StreamIn takes an ordinary tar stream;
+// ReceiveDelta takes a `btrfs send -p parent` stream and must be piped
+// straight into `btrfs receive`, never through a tar extractor.
+type ReplicationDestination interface {
+	HasHandle(handle string) (bool, error)
+	StreamIn(handle string, privileged bool, tarStream io.Reader) error
+	ReceiveDelta(handle string, parent string, privileged bool, sendStream io.Reader) error
+}
+
+// SendDriver produces the byte stream a Replicator hands to a destination:
+// SendDelta a `btrfs send -p parent` stream for the destination's
+// ReceiveDelta, SendFull a full tar stream, which the Replicator uses
+// whenever there's no usable parent on the other end.
+type SendDriver interface {
+	SendFull(handle string) (io.ReadCloser, error)
+	SendDelta(handle string, parent string) (io.ReadCloser, error)
+}
+
+// ReceiveDriver performs the receiving half of a btrfs-send replication,
+// on the destination baggageclaim: it pipes sendStream straight into
+// `btrfs receive`, landing the result at destPath, a COW snapshot of
+// parentPath already created by the caller.
+type ReceiveDriver interface {
+	ReceiveDelta(destPath string, parentPath string, sendStream io.Reader) error
+}
+
+// Replicator pushes a copy of a volume to another baggageclaim, inspired
+// by SeaweedFS's inter-volume replication: when the destination already
+// holds the volume's parent, it requests a `btrfs send -p` delta instead
+// of re-streaming the whole tree.
+type Replicator struct {
+	driver SendDriver
+}
+
+// NewReplicator constructs a Replicator that reads volumes via driver.
+func NewReplicator(driver SendDriver) *Replicator {
+	return &Replicator{driver: driver}
+}
+
+// Replicate streams handle (whose parent, if any, is parentHandle) to
+// dest, landing it there as newHandle.
+func (r *Replicator) Replicate(dest ReplicationDestination, handle string, parentHandle string, newHandle string, privileged bool) error {
+	if parentHandle != "" {
+		hasParent, err := dest.HasHandle(parentHandle)
+		if err != nil {
+			return err
+		}
+
+		if hasParent {
+			return r.replicateDelta(dest, handle, parentHandle, newHandle, privileged)
+		}
+	}
+
+	return r.replicateFull(dest, handle, newHandle, privileged)
+}
+
+func (r *Replicator) replicateDelta(dest ReplicationDestination, handle string, parentHandle string, newHandle string, privileged bool) error {
+	delta, err := r.driver.SendDelta(handle, parentHandle)
+	if err != nil {
+		return err
+	}
+	defer delta.Close()
+
+	return dest.ReceiveDelta(newHandle, parentHandle, privileged, delta)
+}
+
+func (r *Replicator) replicateFull(dest ReplicationDestination, handle string, newHandle string, privileged bool) error {
+	full, err := r.driver.SendFull(handle)
+	if err != nil {
+		return err
+	}
+	defer full.Close()
+
+	return dest.StreamIn(newHandle, privileged, full)
+}
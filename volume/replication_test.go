@@ -0,0 +1,142 @@
+package volume_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+type fakeSendDriver struct {
+	full  map[string][]byte
+	delta map[string][]byte
+}
+
+func (f *fakeSendDriver) SendFull(handle string) (io.ReadCloser, error) {
+	contents, found := f.full[handle]
+	if !found {
+		return nil, errors.New("no such handle")
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
+
+func (f *fakeSendDriver) SendDelta(handle string, parent string) (io.ReadCloser, error) {
+	contents, found := f.delta[handle]
+	if !found {
+		return nil, errors.New("no delta available")
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(contents)), nil
+}
+
+// fakeDestination records which of the two receive paths a Replicator
+// called, so tests can tell a mishandled tar-vs-send stream apart from a
+// merely wrong body.
+type fakeDestination struct {
+	handles map[string]bool
+
+	calledStreamIn     bool
+	calledReceiveDelta bool
+
+	receivedHandle  string
+	receivedParent  string
+	receivedPrivate bool
+	receivedBody    []byte
+}
+
+func (f *fakeDestination) HasHandle(handle string) (bool, error) {
+	return f.handles[handle], nil
+}
+
+func (f *fakeDestination) StreamIn(handle string, privileged bool, tarStream io.Reader) error {
+	body, err := ioutil.ReadAll(tarStream)
+	if err != nil {
+		return err
+	}
+
+	f.calledStreamIn = true
+	f.receivedHandle = handle
+	f.receivedPrivate = privileged
+	f.receivedBody = body
+
+	return nil
+}
+
+func (f *fakeDestination) ReceiveDelta(handle string, parent string, privileged bool, sendStream io.Reader) error {
+	body, err := ioutil.ReadAll(sendStream)
+	if err != nil {
+		return err
+	}
+
+	f.calledReceiveDelta = true
+	f.receivedHandle = handle
+	f.receivedParent = parent
+	f.receivedPrivate = privileged
+	f.receivedBody = body
+
+	return nil
+}
+
+var _ = Describe("Replicator", func() {
+	var (
+		driver     *fakeSendDriver
+		dest       *fakeDestination
+		replicator *volume.Replicator
+	)
+
+	BeforeEach(func() {
+		driver = &fakeSendDriver{
+			full:  map[string][]byte{"some-handle": []byte("full-stream")},
+			delta: map[string][]byte{"some-handle": []byte("delta-stream")},
+		}
+		dest = &fakeDestination{handles: map[string]bool{}}
+		replicator = volume.NewReplicator(driver)
+	})
+
+	Context("when the volume has no parent", func() {
+		It("sends a full stream via StreamIn", func() {
+			err := replicator.Replicate(dest, "some-handle", "", "new-handle", true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dest.calledStreamIn).To(BeTrue())
+			Expect(dest.calledReceiveDelta).To(BeFalse())
+			Expect(dest.receivedHandle).To(Equal("new-handle"))
+			Expect(dest.receivedPrivate).To(BeTrue())
+			Expect(dest.receivedBody).To(Equal([]byte("full-stream")))
+		})
+	})
+
+	Context("when the volume has a parent the destination lacks", func() {
+		It("falls back to a full stream via StreamIn", func() {
+			err := replicator.Replicate(dest, "some-handle", "parent-handle", "new-handle", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dest.calledStreamIn).To(BeTrue())
+			Expect(dest.calledReceiveDelta).To(BeFalse())
+			Expect(dest.receivedBody).To(Equal([]byte("full-stream")))
+		})
+	})
+
+	Context("when the destination already has the parent", func() {
+		BeforeEach(func() {
+			dest.handles["parent-handle"] = true
+		})
+
+		It("sends a delta via the dedicated ReceiveDelta path, not StreamIn", func() {
+			err := replicator.Replicate(dest, "some-handle", "parent-handle", "new-handle", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dest.calledReceiveDelta).To(BeTrue())
+			Expect(dest.calledStreamIn).To(BeFalse())
+			Expect(dest.receivedHandle).To(Equal("new-handle"))
+			Expect(dest.receivedParent).To(Equal("parent-handle"))
+			Expect(dest.receivedBody).To(Equal([]byte("delta-stream")))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// tarHeaderBytes is the size of a tar header block. This ignores GNU long
+// name/link extensions, which is fine for the paths baggageclaim produces.
+const tarHeaderBytes = 512
+
+const trailerBytes = 1024 // the two zeroed blocks that terminate a tar archive
+
+// TarEntry records where a single file lands in the deterministic tar
+// stream StreamOut produces for a volume.
+type TarEntry struct {
+	Name         string `json:"name"`
+	Offset       int64  `json:"offset"`
+	ContentBytes int64  `json:"content_bytes"`
+}
+
+func (e TarEntry) size() int64 {
+	return tarHeaderBytes + paddedSize(e.ContentBytes)
+}
+
+// TarIndex is a lazily-built, on-disk record of a volume's tar layout,
+// invalidated on any write so it is rebuilt the next time the volume is
+// streamed out.
+type TarIndex struct {
+	Entries []TarEntry `json:"entries"`
+	Total   int64      `json:"total"`
+}
+
+func paddedSize(size int64) int64 {
+	if size%tarHeaderBytes == 0 {
+		return size
+	}
+
+	return size + (tarHeaderBytes - size%tarHeaderBytes)
+}
+
+// BuildTarIndex walks root and records the byte offset, within the tar
+// stream StreamOut would produce, of every entry - sorted lexicographically,
+// matching the order StreamOut tars them in.
+func BuildTarIndex(root string) (*TarIndex, error) {
+	var names []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	index := &TarIndex{}
+	var offset int64
+
+	for _, name := range names {
+		info, err := os.Lstat(filepath.Join(root, name))
+		if err != nil {
+			return nil, err
+		}
+
+		var contentBytes int64
+		if info.Mode().IsRegular() {
+			contentBytes = info.Size()
+		}
+
+		entry := TarEntry{
+			Name:         name,
+			Offset:       offset,
+			ContentBytes: contentBytes,
+		}
+
+		index.Entries = append(index.Entries, entry)
+		offset += entry.size()
+	}
+
+	index.Total = offset + trailerBytes
+
+	return index, nil
+}
+
+// TarIndexStore persists each volume's TarIndex under dir, keyed by
+// handle, so it survives across requests but is rebuilt whenever the
+// volume's contents change.
+type TarIndexStore struct {
+	dir string
+}
+
+func NewTarIndexStore(dir string) *TarIndexStore {
+	return &TarIndexStore{dir: dir}
+}
+
+func (s *TarIndexStore) path(handle string) string {
+	return filepath.Join(s.dir, handle+".tar-index.json")
+}
+
+// Get returns the cached index for handle, building and caching one from
+// root if none exists yet.
+func (s *TarIndexStore) Get(handle string, root string) (*TarIndex, error) {
+	cached, err := s.load(handle)
+	if err == nil {
+		return cached, nil
+	}
+
+	index, err := BuildTarIndex(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, s.save(handle, index)
+}
+
+// Invalidate discards handle's cached index; call this on any write to the
+// volume (stream-in, property/TTL changes that touch its contents, etc.)
+// so the next stream-out rebuilds it.
+func (s *TarIndexStore) Invalidate(handle string) error {
+	err := os.Remove(s.path(handle))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *TarIndexStore) load(handle string) (*TarIndex, error) {
+	contents, err := ioutil.ReadFile(s.path(handle))
+	if err != nil {
+		return nil, err
+	}
+
+	index := &TarIndex{}
+	if err := json.Unmarshal(contents, index); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (s *TarIndexStore) save(handle string, index *TarIndex) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.path(handle), contents, 0644)
+}
+
+// StreamOutFrom writes to w the portion of root's tar stream starting at
+// offset, skipping whole entries that end before offset and, for the
+// entry straddling it, the already-sent prefix of that entry's bytes.
+func StreamOutFrom(w io.Writer, root string, index *TarIndex, offset int64) error {
+	if offset < 0 || offset > index.Total {
+		return fmt.Errorf("tar-index: offset %d out of range [0,%d]", offset, index.Total)
+	}
+
+	for _, entry := range index.Entries {
+		if entry.Offset+entry.size() <= offset {
+			continue
+		}
+
+		raw, err := renderEntry(root, entry)
+		if err != nil {
+			return err
+		}
+
+		if entry.Offset < offset {
+			raw = raw[offset-entry.Offset:]
+		}
+
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+
+	trailerStart := index.Total - trailerBytes
+	trailer := make([]byte, trailerBytes)
+
+	if trailerStart < offset {
+		trailer = trailer[offset-trailerStart:]
+	}
+
+	_, err := w.Write(trailer)
+	return err
+}
+
+// renderEntry produces the exact header+content+padding bytes StreamOut
+// would emit for entry, with no archive trailer.
+func renderEntry(root string, entry TarEntry) ([]byte, error) {
+	path := filepath.Join(root, entry.Name)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var linkTarget string
+	if info.Mode()&os.ModeSymlink != 0 {
+		linkTarget, err = os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, linkTarget)
+	if err != nil {
+		return nil, err
+	}
+	header.Name = entry.Name
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+
+	if info.Mode().IsRegular() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		if _, err := io.Copy(tw, f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
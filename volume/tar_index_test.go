@@ -0,0 +1,108 @@
+package volume_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+var _ = Describe("TarIndex", func() {
+	var root string
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "tar-index")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ioutil.WriteFile(filepath.Join(root, "a"), []byte("hello"), 0644)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(root, "dir"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "dir", "b"), bytes.Repeat([]byte("x"), 1024), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	fullStream := func() []byte {
+		buf := new(bytes.Buffer)
+		tw := tar.NewWriter(buf)
+
+		for _, rel := range []string{"a", "dir", "dir/b"} {
+			info, err := os.Lstat(filepath.Join(root, rel))
+			Expect(err).NotTo(HaveOccurred())
+
+			header, err := tar.FileInfoHeader(info, "")
+			Expect(err).NotTo(HaveOccurred())
+			header.Name = rel
+
+			Expect(tw.WriteHeader(header)).To(Succeed())
+
+			if info.Mode().IsRegular() {
+				contents, err := ioutil.ReadFile(filepath.Join(root, rel))
+				Expect(err).NotTo(HaveOccurred())
+				_, err = tw.Write(contents)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+
+		Expect(tw.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	Describe("BuildTarIndex", func() {
+		It("records every entry in lexicographic order with an increasing offset", func() {
+			index, err := volume.BuildTarIndex(root)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(index.Entries).To(HaveLen(3))
+			Expect(index.Entries[0].Name).To(Equal("a"))
+			Expect(index.Entries[1].Name).To(Equal("dir"))
+			Expect(index.Entries[2].Name).To(Equal("dir/b"))
+
+			Expect(index.Entries[0].Offset).To(Equal(int64(0)))
+			Expect(index.Entries[1].Offset).To(BeNumerically(">", index.Entries[0].Offset))
+			Expect(index.Entries[2].Offset).To(BeNumerically(">", index.Entries[1].Offset))
+
+			Expect(index.Total).To(BeNumerically(">", index.Entries[2].Offset))
+		})
+	})
+
+	Describe("StreamOutFrom", func() {
+		It("reproduces the full stream when given a zero offset", func() {
+			index, err := volume.BuildTarIndex(root)
+			Expect(err).NotTo(HaveOccurred())
+
+			buf := new(bytes.Buffer)
+			Expect(volume.StreamOutFrom(buf, root, index, 0)).To(Succeed())
+
+			Expect(buf.Bytes()).To(Equal(fullStream()))
+		})
+
+		It("resumes mid-stream at an arbitrary offset", func() {
+			index, err := volume.BuildTarIndex(root)
+			Expect(err).NotTo(HaveOccurred())
+
+			offset := index.Entries[2].Offset + 100
+
+			buf := new(bytes.Buffer)
+			Expect(volume.StreamOutFrom(buf, root, index, offset)).To(Succeed())
+
+			Expect(buf.Bytes()).To(Equal(fullStream()[offset:]))
+		})
+
+		It("rejects an offset beyond the end of the stream", func() {
+			index, err := volume.BuildTarIndex(root)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = volume.StreamOutFrom(ioutil.Discard, root, index, index.Total+1)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,269 @@
+package volume
+
+import (
+	"context"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+
+	"github.com/concourse/baggageclaim/metrics"
+)
+
+// VacuumCandidateKind describes why a subvolume was selected for vacuuming.
+type VacuumCandidateKind string
+
+const (
+	DeadParent       VacuumCandidateKind = "dead-parent"
+	Fragmented       VacuumCandidateKind = "fragmented"
+	OrphanedTmpStage VacuumCandidateKind = "orphaned-tmp-stage"
+)
+
+// VacuumCandidate is a subvolume (or, for orphaned staging directories, a
+// bare path) identified by a VacuumScanner as worth reclaiming.
+type VacuumCandidate struct {
+	Handle string
+	Path   string
+	Kind   VacuumCandidateKind
+}
+
+// VacuumResult reports the outcome of acting on a single VacuumCandidate.
+type VacuumResult struct {
+	VacuumCandidate
+	Err      error
+	Duration time.Duration
+}
+
+// FragmentationChecker measures how fragmented a subvolume's extents are,
+// e.g. via `btrfs filesystem du` or an extent count heuristic.
+type FragmentationChecker interface {
+	FragmentationRatio(path string) (float64, error)
+}
+
+// VacuumDriver performs the reclaiming actions a Vacuum run decides on.
+// The btrfs driver implements this with `btrfs subvolume delete` and
+// `btrfs filesystem defragment -r`; the naive driver fakes it for tests.
+type VacuumDriver interface {
+	DeleteSubvolume(path string) error
+	DefragmentSubvolume(path string) error
+}
+
+// VacuumScanner enumerates handles and their live-descendant/TTL state so
+// the Vacuum can decide which subvolumes are dead-parent candidates, any
+// leftover tmp/staging paths left behind by a crashed stream, and every
+// live (in-use) subvolume worth checking for fragmentation.
+type VacuumScanner interface {
+	DeadParents() ([]VacuumCandidate, error)
+	OrphanedStagingPaths() ([]VacuumCandidate, error)
+	LiveSubvolumes() ([]VacuumCandidate, error)
+}
+
+// Vacuum periodically walks a volume repository looking for subvolumes
+// that are safe to reclaim or worth defragmenting, inspired by SeaweedFS's
+// batched volume compaction.
+type Vacuum struct {
+	logger lager.Logger
+
+	scanner    VacuumScanner
+	driver     VacuumDriver
+	checker    FragmentationChecker
+	locker     HandleLocker
+	threshold  float64
+	handleTTLs func() (map[string]time.Duration, error)
+
+	timeout time.Duration
+}
+
+// HandleLocker is satisfied by the volume repository: it serializes access
+// to a handle so a vacuum pass never races a concurrent stream or destroy.
+type HandleLocker interface {
+	LockHandle(handle string) (unlock func())
+}
+
+// NewVacuum constructs a Vacuum. timeout bounds how long a single
+// candidate is given to be reclaimed before the run moves on and reports
+// failure for it.
+func NewVacuum(
+	logger lager.Logger,
+	scanner VacuumScanner,
+	driver VacuumDriver,
+	checker FragmentationChecker,
+	locker HandleLocker,
+	fragmentationThreshold float64,
+	timeout time.Duration,
+) *Vacuum {
+	return &Vacuum{
+		logger:    logger,
+		scanner:   scanner,
+		driver:    driver,
+		checker:   checker,
+		locker:    locker,
+		threshold: fragmentationThreshold,
+		timeout:   timeout,
+	}
+}
+
+// Run performs a single vacuum pass, reporting results on the returned
+// channel as each candidate finishes (the channel is closed once every
+// candidate has been processed or timed out).
+func (v *Vacuum) Run(ctx context.Context) <-chan VacuumResult {
+	results := make(chan VacuumResult)
+
+	go func() {
+		defer close(results)
+
+		candidates, err := v.candidates()
+		if err != nil {
+			v.logger.Error("failed-to-scan-for-candidates", err)
+			return
+		}
+
+		for _, candidate := range candidates {
+			select {
+			case <-ctx.Done():
+				return
+			case results <- v.reclaim(ctx, candidate):
+			}
+		}
+	}()
+
+	return results
+}
+
+// RunPeriodically starts a goroutine that calls Run every interval until
+// ctx is cancelled. Each result is logged; callers that need the per-run
+// detail should use Run directly (e.g. from the /vacuum admin endpoint).
+func (v *Vacuum) RunPeriodically(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for result := range v.Run(ctx) {
+					if result.Err != nil {
+						v.logger.Error("vacuum-failed", result.Err, lager.Data{
+							"handle": result.Handle,
+							"kind":   string(result.Kind),
+						})
+						continue
+					}
+
+					v.logger.Info("vacuumed", lager.Data{
+						"handle": result.Handle,
+						"kind":   string(result.Kind),
+					})
+				}
+			}
+		}
+	}()
+}
+
+func (v *Vacuum) candidates() ([]VacuumCandidate, error) {
+	var candidates []VacuumCandidate
+
+	deadParents, err := v.scanner.DeadParents()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates = append(candidates, deadParents...)
+
+	staging, err := v.scanner.OrphanedStagingPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates = append(candidates, staging...)
+
+	live, err := v.scanner.LiveSubvolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	dead := make(map[string]bool, len(deadParents))
+	for _, deadParent := range deadParents {
+		dead[deadParent.Handle] = true
+	}
+
+	var fragmentable []VacuumCandidate
+	for _, candidate := range live {
+		if dead[candidate.Handle] {
+			// already up for deletion this pass - defragmenting it after
+			// DeleteSubvolume runs would just fail against a path that's
+			// gone.
+			continue
+		}
+
+		fragmentable = append(fragmentable, candidate)
+	}
+
+	fragmented, err := v.fragmentedCandidates(fragmentable)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(candidates, fragmented...), nil
+}
+
+// fragmentedCandidates checks the fragmentation ratio of each live
+// subvolume (scanned is the live-subvolume list candidates just fetched,
+// already excluding anything selected for deletion this pass) and reports
+// any that exceed the threshold.
+func (v *Vacuum) fragmentedCandidates(scanned []VacuumCandidate) ([]VacuumCandidate, error) {
+	var fragmented []VacuumCandidate
+
+	for _, candidate := range scanned {
+		ratio, err := v.checker.FragmentationRatio(candidate.Path)
+		if err != nil {
+			v.logger.Error("failed-to-check-fragmentation", err, lager.Data{"handle": candidate.Handle})
+			continue
+		}
+
+		if ratio > v.threshold {
+			fragmented = append(fragmented, VacuumCandidate{
+				Handle: candidate.Handle,
+				Path:   candidate.Path,
+				Kind:   Fragmented,
+			})
+		}
+	}
+
+	return fragmented, nil
+}
+
+func (v *Vacuum) reclaim(ctx context.Context, candidate VacuumCandidate) VacuumResult {
+	start := time.Now()
+
+	unlock := v.locker.LockHandle(candidate.Handle)
+
+	done := make(chan error, 1)
+
+	// the handle stays locked until the driver call returns, even if this
+	// candidate times out and the run moves on without it.
+	go func() {
+		defer unlock()
+
+		switch candidate.Kind {
+		case Fragmented:
+			done <- v.driver.DefragmentSubvolume(candidate.Path)
+		default:
+			done <- v.driver.DeleteSubvolume(candidate.Path)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return VacuumResult{VacuumCandidate: candidate, Err: ctx.Err(), Duration: time.Since(start)}
+	case <-time.After(v.timeout):
+		return VacuumResult{VacuumCandidate: candidate, Err: ErrVacuumTimedOut, Duration: time.Since(start)}
+	case err := <-done:
+		if err == nil && candidate.Kind == DeadParent {
+			metrics.TTLExpirationsTotal.Inc()
+		}
+
+		return VacuumResult{VacuumCandidate: candidate, Err: err, Duration: time.Since(start)}
+	}
+}
@@ -0,0 +1,5 @@
+package volume
+
+import "errors"
+
+var ErrVacuumTimedOut = errors.New("vacuum: timed out reclaiming volume")
@@ -0,0 +1,166 @@
+package volume_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/lager/lagertest"
+
+	"github.com/concourse/baggageclaim/volume"
+)
+
+type fakeScanner struct {
+	deadParents []volume.VacuumCandidate
+	staging     []volume.VacuumCandidate
+	live        []volume.VacuumCandidate
+}
+
+func (f *fakeScanner) DeadParents() ([]volume.VacuumCandidate, error) {
+	return f.deadParents, nil
+}
+
+func (f *fakeScanner) OrphanedStagingPaths() ([]volume.VacuumCandidate, error) {
+	return f.staging, nil
+}
+
+func (f *fakeScanner) LiveSubvolumes() ([]volume.VacuumCandidate, error) {
+	return f.live, nil
+}
+
+type fakeChecker struct {
+	ratios map[string]float64
+}
+
+func (f *fakeChecker) FragmentationRatio(path string) (float64, error) {
+	return f.ratios[path], nil
+}
+
+type fakeDriver struct {
+	mu      sync.Mutex
+	deleted []string
+	defragd []string
+}
+
+func (f *fakeDriver) DeleteSubvolume(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, path)
+	return nil
+}
+
+func (f *fakeDriver) DefragmentSubvolume(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.defragd = append(f.defragd, path)
+	return nil
+}
+
+type fakeLocker struct{}
+
+func (fakeLocker) LockHandle(string) func() {
+	return func() {}
+}
+
+var _ = Describe("Vacuum", func() {
+	var (
+		scanner *fakeScanner
+		checker *fakeChecker
+		driver  *fakeDriver
+		vac     *volume.Vacuum
+	)
+
+	BeforeEach(func() {
+		scanner = &fakeScanner{}
+		checker = &fakeChecker{ratios: map[string]float64{}}
+		driver = &fakeDriver{}
+
+		vac = volume.NewVacuum(
+			lagertest.NewTestLogger("vacuum"),
+			scanner,
+			driver,
+			checker,
+			fakeLocker{},
+			0.5,
+			time.Second,
+		)
+	})
+
+	collect := func(vac *volume.Vacuum) []volume.VacuumResult {
+		var results []volume.VacuumResult
+		for result := range vac.Run(context.Background()) {
+			results = append(results, result)
+		}
+		return results
+	}
+
+	Context("when a dead parent has no live descendants", func() {
+		BeforeEach(func() {
+			scanner.deadParents = []volume.VacuumCandidate{
+				{Handle: "dead-parent", Path: "/volumes/dead-parent", Kind: volume.DeadParent},
+			}
+		})
+
+		It("deletes the subvolume", func() {
+			results := collect(vac)
+
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Err).NotTo(HaveOccurred())
+			Expect(driver.deleted).To(ContainElement("/volumes/dead-parent"))
+		})
+	})
+
+	Context("when a live subvolume's fragmentation ratio exceeds the threshold", func() {
+		BeforeEach(func() {
+			scanner.live = []volume.VacuumCandidate{
+				{Handle: "fragmented", Path: "/volumes/fragmented"},
+			}
+			checker.ratios["/volumes/fragmented"] = 0.9
+		})
+
+		It("defragments the subvolume", func() {
+			results := collect(vac)
+
+			Expect(results).To(HaveLen(1))
+			Expect(driver.defragd).To(ContainElement("/volumes/fragmented"))
+			Expect(driver.deleted).To(BeEmpty())
+		})
+	})
+
+	Context("when a dead parent is also reported as a live subvolume exceeding the threshold", func() {
+		BeforeEach(func() {
+			scanner.deadParents = []volume.VacuumCandidate{
+				{Handle: "dead-and-fragmented", Path: "/volumes/dead-and-fragmented", Kind: volume.DeadParent},
+			}
+			scanner.live = []volume.VacuumCandidate{
+				{Handle: "dead-and-fragmented", Path: "/volumes/dead-and-fragmented"},
+			}
+			checker.ratios["/volumes/dead-and-fragmented"] = 0.9
+		})
+
+		It("only deletes it, without also trying to defragment the now-deleted path", func() {
+			results := collect(vac)
+
+			Expect(results).To(HaveLen(1))
+			Expect(driver.deleted).To(ContainElement("/volumes/dead-and-fragmented"))
+			Expect(driver.defragd).To(BeEmpty())
+		})
+	})
+
+	Context("when there are orphaned tmp/staging paths", func() {
+		BeforeEach(func() {
+			scanner.staging = []volume.VacuumCandidate{
+				{Path: "/volumes/.tmp-stream-123", Kind: volume.OrphanedTmpStage},
+			}
+		})
+
+		It("removes them", func() {
+			results := collect(vac)
+
+			Expect(results).To(HaveLen(1))
+			Expect(driver.deleted).To(ContainElement("/volumes/.tmp-stream-123"))
+		})
+	})
+})